@@ -1,47 +1,74 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"github.com/seedtray/logReader"
-	"github.com/spf13/afero"
-	"io"
 	"log"
+	"strings"
 )
 
-// Prints a file's lines and watches for further appends. Similar to tail -f.
+// Prints a file's lines and watches for further appends. Similar to tail -f. Besides plain file paths, it
+// also accepts the URL schemes understood by logReader.Open (udp://, unixgram://, tcp:// and unix://), so
+// it can be pointed at a syslog-style socket instead of a file on disk.
 // Each line is printed prefixed by a number which can be used as a starting point on a later call.
 func main() {
 
 	var position = flag.Int64("resume", 0, "Resume from position. By default it starts at the beginning.")
+	var poll = flag.Bool("poll", false, "Use stat-based polling instead of native file change notifications.")
+	var reopen = flag.Bool("reopen", false, "Reopen the file if it's truncated or rotated, like tail -F.")
+	var maxLinesPerSecond = flag.Float64("max-lines-per-second", 0, "Cap the rate lines are printed at. 0 means unlimited.")
+	var burst = flag.Int("burst", 0, "Lines allowed to print back-to-back before rate limiting kicks in. Defaults to 1.")
+	var dropWhenLimited = flag.Bool("drop-when-limited", false, "Drop lines exceeding the rate limit instead of waiting to print them.")
+	var maxLineSize = flag.Int("max-line-size", 0, "Split physical lines longer than this many bytes instead of growing memory unboundedly. 0 means unlimited.")
 	flag.Parse()
-	filename := flag.Arg(0)
+	target := flag.Arg(0)
 
-	watcher := logReader.NewOsPollingFileWatcher(filename)
-	fileUpdates, stop := watcher.Start()
-	defer stop()
+	if scheme, isSocket := socketScheme(target); isSocket {
+		stream, err := logReader.Open(target)
+		if err != nil {
+			log.Fatalln(err)
+		}
+		defer stream.Close()
+		log.Printf("listening on %s", scheme)
+		printLines(stream.Lines())
+		return
+	}
 
-	fs := afero.NewOsFs()
-	file, err := fs.Open(filename)
+	tail, err := logReader.NewTail(target, *position, *poll, logReader.TailConfig{
+		ReOpen:            *reopen,
+		MaxLinesPerSecond: *maxLinesPerSecond,
+		Burst:             *burst,
+		DropWhenLimited:   *dropWhenLimited,
+		MaxLineSize:       *maxLineSize,
+	})
 	if err != nil {
 		log.Fatalln(err)
 	}
-	lineReader, err := logReader.NewLineReaderAtPosition(file, *position, false)
-	if err != nil {
-		log.Fatalln(err)
+	defer tail.Close(context.Background())
+	printLines(tail.Lines())
+}
+
+// socketScheme reports the scheme of target if it has one other than file, in which case target is handed
+// to logReader.Open, which rejects schemes it doesn't support. Plain file paths and file:// URLs report false.
+func socketScheme(target string) (string, bool) {
+	scheme, _, found := strings.Cut(target, "://")
+	if !found || scheme == "file" {
+		return "", false
 	}
+	return scheme, true
+}
 
-	for {
-		line, position, err := lineReader.ReadLine()
-		if err == nil {
-			fmt.Printf("%10d: %s\n", position, string(line))
-		} else if err == io.EOF {
-			_, ok := <-fileUpdates
-			if !ok {
-				log.Fatalln(watcher.Err())
-			}
-		} else {
-			log.Fatalln(err)
+func printLines(lines <-chan logReader.Line) {
+	for line := range lines {
+		if line.Err != nil {
+			log.Fatalln(line.Err)
+		}
+		sep := "\n"
+		if line.Continued {
+			sep = ""
 		}
+		fmt.Printf("%10d: %s%s", line.Offset, line.Text, sep)
 	}
 }
@@ -1,6 +1,25 @@
 package logReader
 
-type UpdateSignal struct{}
+// UpdateKind identifies the nature of a file change reported by an UpdateSignal.
+type UpdateKind int
+
+const (
+	// Appended means the watched file grew: new bytes are available after the last known position.
+	Appended UpdateKind = iota
+	// Truncated means the watched file shrank in place, e.g. a copytruncate-style log rotation.
+	Truncated
+	// Rotated means the watched file was replaced by a different file under the same name, e.g. a
+	// rename-and-recreate log rotation.
+	Rotated
+)
+
+// UpdateSignal reports a single change observed on a watched file.
+type UpdateSignal struct {
+	Kind UpdateKind
+}
+
+// updateSignal is the zero-value signal, used by watchers that can't tell Appended from Truncated/Rotated.
+var updateSignal = UpdateSignal{Kind: Appended}
 
 type FileWatcher interface {
 	// Start starts watching the file.
@@ -11,3 +30,14 @@ type FileWatcher interface {
 	// Err returns any error condition found while watching.
 	Err() error
 }
+
+// NewFileWatcher makes a FileWatcher for filename, picking between stat-based polling and native OS file
+// change notifications (inotify/kqueue/ReadDirectoryChangesW). Pass poll=true to force
+// PollingFileWatcher, e.g. when watching a filesystem (network mounts, some container overlays) where
+// native notifications aren't delivered reliably.
+func NewFileWatcher(filename string, poll bool) FileWatcher {
+	if poll {
+		return NewOsPollingFileWatcher(filename)
+	}
+	return NewFsNotifyFileWatcher(filename)
+}
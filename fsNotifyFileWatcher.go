@@ -0,0 +1,86 @@
+package logReader
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// FsNotifyFileWatcher is a FileWatcher backed by the host OS's native file change notifications
+// (inotify on Linux, kqueue on BSD/macOS, ReadDirectoryChangesW on Windows), as opposed to
+// PollingFileWatcher's stat-based polling.
+type FsNotifyFileWatcher struct {
+	filename string
+	err      error
+}
+
+var _ FileWatcher = &FsNotifyFileWatcher{}
+
+// NewFsNotifyFileWatcher makes a new FsNotifyFileWatcher for the given file.
+func NewFsNotifyFileWatcher(filename string) *FsNotifyFileWatcher {
+	return &FsNotifyFileWatcher{filename: filename}
+}
+
+func (fw *FsNotifyFileWatcher) Start() (<-chan UpdateSignal, func()) {
+	updates := make(chan UpdateSignal)
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		fw.err = err
+		close(updates)
+		return updates, func() {}
+	}
+	// Watch the parent directory rather than the file itself, so that a rename or create event, as
+	// produced by log rotation, is still observed even though it replaces the inode the watch was
+	// originally attached to.
+	if err := watcher.Add(filepath.Dir(fw.filename)); err != nil {
+		fw.err = err
+		close(updates)
+		_ = watcher.Close()
+		return updates, func() {}
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	go fw.watch(ctx, watcher, updates)
+	return updates, cancel
+}
+
+func (fw *FsNotifyFileWatcher) watch(ctx context.Context, watcher *fsnotify.Watcher, updates chan UpdateSignal) {
+	defer watcher.Close()
+	defer close(updates)
+	dir := filepath.Dir(fw.filename)
+	base := filepath.Base(fw.filename)
+	for {
+		select {
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return
+			}
+			if event.Name == dir && event.Has(fsnotify.Remove|fsnotify.Rename) {
+				// The watched directory itself was removed or renamed away: there's nothing left to watch.
+				fw.err = errors.New("watched directory is gone: " + dir)
+				return
+			}
+			if filepath.Base(event.Name) != base {
+				continue
+			}
+			select {
+			case updates <- updateSignal:
+			case <-ctx.Done():
+				return
+			}
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return
+			}
+			fw.err = err
+			return
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (fw *FsNotifyFileWatcher) Err() error {
+	return fw.err
+}
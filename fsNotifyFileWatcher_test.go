@@ -0,0 +1,65 @@
+package logReader
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+const fsNotifyTestTimeout = 2 * time.Second
+
+func TestFsNotifyNotifiesOnAppend(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), t.Name())
+	file, err := os.OpenFile(filename, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	watcher := NewFsNotifyFileWatcher(filename)
+	updates, stop := watcher.Start()
+	defer stop()
+
+	if _, err = fmt.Fprintln(file, "An update"); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-updates:
+	case <-time.After(fsNotifyTestTimeout):
+		t.Errorf("watcher didn't signal a file change.")
+	}
+}
+
+// Log rotation typically renames the watched file away and creates a new file under the same name.
+// Since FsNotifyFileWatcher watches the parent directory, it should still notice.
+func TestFsNotifyNotifiesOnRotation(t *testing.T) {
+	dir := t.TempDir()
+	filename := filepath.Join(dir, t.Name())
+	if err := os.WriteFile(filename, []byte("original\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	watcher := NewFsNotifyFileWatcher(filename)
+	updates, stop := watcher.Start()
+	defer stop()
+
+	if err := os.Rename(filename, filename+".1"); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-updates:
+	case <-time.After(fsNotifyTestTimeout):
+		t.Errorf("watcher didn't signal the rename away of the watched file.")
+	}
+
+	if err := os.WriteFile(filename, []byte("rotated\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-updates:
+	case <-time.After(fsNotifyTestTimeout):
+		t.Errorf("watcher didn't signal the creation of the new file.")
+	}
+}
@@ -14,16 +14,42 @@ type LineReader struct {
 	nextPosition int64
 	buffer       []byte
 	finalized    bool
+	maxLineSize  int
 }
 
+// LineReaderOptions holds the optional settings accepted by NewLineReaderWithOptions.
+type LineReaderOptions struct {
+	// MaxLineSize sets the size of the underlying read buffer. Whenever that buffer fills up without a
+	// newline, ReadLine flushes what it has accumulated so far as a synthetic line and returns ErrLineTooLong
+	// instead of growing lr.buffer further. Bytes of a line read before an io.EOF stay accumulated and are
+	// not flushed on their own, so a line that arrives in several appends can come back longer than
+	// MaxLineSize, either whole or as an ErrLineTooLong chunk. Zero, the default, means unlimited.
+	MaxLineSize int
+}
+
+// ErrLineTooLong is returned by ReadLine, together with the chunk read so far, when a physical line grows
+// past the configured MaxLineSize. The next ReadLine call picks up where this one left off, so a caller
+// that wants the original line back can concatenate chunks until one is returned with a nil or io.EOF error.
+var ErrLineTooLong = errors.New("line exceeds MaxLineSize")
+
 // NewLineReader makes a new LineReader from a Reader.
 func NewLineReader(reader io.Reader) *LineReader {
-	return &LineReader{bufio.NewReader(reader), 0, newBuffer(), false}
+	return NewLineReaderWithOptions(reader, LineReaderOptions{})
+}
+
+// NewLineReaderWithOptions makes a new LineReader from a Reader, like NewLineReader, configured with opts.
+func NewLineReaderWithOptions(reader io.Reader, opts LineReaderOptions) *LineReader {
+	return &LineReader{bufio.NewReaderSize(reader, lineBufferSize(opts)), 0, newBuffer(), false, opts.MaxLineSize}
 }
 
 // NewLineReaderAtPosition makes a line scanner that will start scanning at a specific position within the given file.
 // fileFinalized tells the file is not expected to be further appended to. See ReadLine
 func NewLineReaderAtPosition(source io.ReadSeeker, position int64, fileFinalized bool) (*LineReader, error) {
+	return NewLineReaderAtPositionWithOptions(source, position, fileFinalized, LineReaderOptions{})
+}
+
+// NewLineReaderAtPositionWithOptions makes a line scanner like NewLineReaderAtPosition, configured with opts.
+func NewLineReaderAtPositionWithOptions(source io.ReadSeeker, position int64, fileFinalized bool, opts LineReaderOptions) (*LineReader, error) {
 	offset, err := source.Seek(position, io.SeekStart)
 	if err != nil {
 		return nil, err
@@ -31,10 +57,26 @@ func NewLineReaderAtPosition(source io.ReadSeeker, position int64, fileFinalized
 	if offset != position {
 		return nil, errors.New("cant reposition within file")
 	}
-	lineReader := &LineReader{reader: bufio.NewReader(source), nextPosition: position, buffer: newBuffer(), finalized: fileFinalized}
+	lineReader := &LineReader{
+		reader:       bufio.NewReaderSize(source, lineBufferSize(opts)),
+		nextPosition: position,
+		buffer:       newBuffer(),
+		finalized:    fileFinalized,
+		maxLineSize:  opts.MaxLineSize,
+	}
 	return lineReader, nil
 }
 
+// lineBufferSize picks the underlying bufio.Reader's buffer size so that ReadLine's ErrLineTooLong chunks
+// are cut at MaxLineSize, falling back to DefaultLineBufferSize when it's unset. bufio.NewReaderSize
+// enforces its own minimum, so a very small MaxLineSize is rounded up to that.
+func lineBufferSize(opts LineReaderOptions) int {
+	if opts.MaxLineSize > 0 {
+		return opts.MaxLineSize
+	}
+	return DefaultLineBufferSize
+}
+
 // ReadLine reads the next line from the input.
 // Returns the scanned line, the position within the file where the next line will start and any occurring error.
 // Typically, either a line is matched, in which case (line, position, nil) is returned, or an error is found and
@@ -50,6 +92,14 @@ func NewLineReaderAtPosition(source io.ReadSeeker, position int64, fileFinalized
 //
 // Newline is either '\n' or '\r\n'. Lines are returned without newline, and they can be empty.
 //
+// If MaxLineSize is set, a physical line growing past it is split: each time MaxLineSize bytes of it are
+// read without finding a newline, ReadLine returns what it has accumulated so far as a synthetic line
+// alongside ErrLineTooLong, resuming from there on each following call until the line's actual newline is
+// found. Chunks are MaxLineSize bytes long when the line is read in one go; bytes of it accumulated before
+// an earlier io.EOF are added to the next chunk, or to the line itself if its newline comes first, so
+// those can be longer. bufio.NewReaderSize
+// enforces its own minimum buffer size, so a MaxLineSize smaller than that is rounded up to it.
+//
 func (lr *LineReader) ReadLine() ([]byte, int64, error) {
 	for {
 		read, readError := lr.reader.ReadSlice('\n')
@@ -66,6 +116,11 @@ func (lr *LineReader) ReadLine() ([]byte, int64, error) {
 			return line, lr.nextPosition, nil
 		}
 		if readError == bufio.ErrBufferFull {
+			if lr.maxLineSize > 0 && len(lr.buffer) >= lr.maxLineSize {
+				chunk := lr.buffer
+				lr.buffer = newBuffer()
+				return chunk, lr.nextPosition, ErrLineTooLong
+			}
 			// we already got the entire buffer as a read fragment and put it in our buffer, so
 			// we're good to carry on
 			continue
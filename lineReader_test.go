@@ -162,6 +162,40 @@ func TestLineCanBeBiggerThanBufferSize(t *testing.T) {
 	assertReadLineFindsLine(t, reader, contents)
 }
 
+// Test that a physical line longer than MaxLineSize is split across successive ReadLine calls, in chunks
+// of exactly MaxLineSize bytes, each flagged with ErrLineTooLong except the final, newline-terminated one.
+func TestMaxLineSizeSplitsLongLines(t *testing.T) {
+	ft := newFileForTest(t)
+	ft.Append(strings.Repeat("x", 50) + "\nshort\n")
+	reader := NewLineReaderWithOptions(ft.Reader, LineReaderOptions{MaxLineSize: 20})
+
+	chunk, _, err := reader.ReadLine()
+	if err != ErrLineTooLong {
+		t.Fatalf("expected ErrLineTooLong, got %v", err)
+	}
+	if string(chunk) != strings.Repeat("x", 20) {
+		t.Errorf("expected a 20 byte chunk, got %q", chunk)
+	}
+
+	chunk, _, err = reader.ReadLine()
+	if err != ErrLineTooLong {
+		t.Fatalf("expected a second ErrLineTooLong, got %v", err)
+	}
+	if string(chunk) != strings.Repeat("x", 20) {
+		t.Errorf("expected another 20 byte chunk, got %q", chunk)
+	}
+
+	rest, _, err := reader.ReadLine()
+	if err != nil {
+		t.Fatalf("expected the remainder of the line to be found, got %v", err)
+	}
+	if string(rest) != strings.Repeat("x", 10) {
+		t.Errorf("expected the remaining 10 x's, got %q", rest)
+	}
+
+	assertReadLineFindsLine(t, reader, "short")
+}
+
 func TestCanResumeAfterOneLineRead(t *testing.T) {
 	ft := newFileForTest(t)
 	ft.Append("line1\nline2\nline3\n")
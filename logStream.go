@@ -0,0 +1,237 @@
+package logReader
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// LogStream is a source of log lines, generalizing Tail beyond files to datagram sockets (each packet is
+// one Line) and stream sockets (newline-delimited via LineReader). See Open.
+type LogStream interface {
+	// Lines returns the channel lines read from the stream are sent on. As with Tail.Lines(), its last
+	// value has a non-nil Err if the stream stopped because of an unrecoverable error, and the channel is
+	// closed right after.
+	Lines() <-chan Line
+
+	// Close stops the stream and releases its resources. It's safe to call multiple times.
+	Close() error
+}
+
+// Open opens a LogStream for rawURL, picking an implementation by scheme:
+//
+//	(no scheme), or file://path   tails the file, like NewTail
+//	udp://host:port               reads one Line per UDP datagram
+//	unixgram:///path              reads one Line per unixgram datagram
+//	tcp://host:port               reads newline-delimited Lines from accepted TCP connections
+//	unix:///path                  reads newline-delimited Lines from accepted Unix socket connections
+//
+// Datagram and stream sources start listening immediately; Open returns once the listener is ready.
+func Open(rawURL string) (LogStream, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	switch u.Scheme {
+	case "", "file":
+		path := u.Path
+		if path == "" {
+			path = rawURL
+		}
+		tail, err := NewTail(path, 0, false, TailConfig{})
+		if err != nil {
+			return nil, err
+		}
+		return &fileLogStream{tail}, nil
+	case "udp":
+		return newDatagramLogStream(u.Scheme, u.Host)
+	case "unixgram":
+		return newDatagramLogStream(u.Scheme, u.Path)
+	case "tcp":
+		return newStreamLogStream(u.Scheme, u.Host)
+	case "unix":
+		return newStreamLogStream(u.Scheme, u.Path)
+	default:
+		return nil, fmt.Errorf("logReader: unsupported scheme %q", u.Scheme)
+	}
+}
+
+// fileLogStream adapts a *Tail to the LogStream interface.
+type fileLogStream struct {
+	tail *Tail
+}
+
+func (s *fileLogStream) Lines() <-chan Line { return s.tail.Lines() }
+
+func (s *fileLogStream) Close() error { return s.tail.Close(context.Background()) }
+
+// datagramLogStream is a LogStream backed by a connectionless socket (UDP or unixgram), where each
+// datagram read becomes one Line.
+type datagramLogStream struct {
+	conn    net.PacketConn
+	lines   chan Line
+	lineNum int
+
+	stop      chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// newDatagramLogStream starts listening on network/address (as accepted by net.ListenPacket) and begins
+// delivering one Line per datagram received.
+func newDatagramLogStream(network, address string) (*datagramLogStream, error) {
+	conn, err := net.ListenPacket(network, address)
+	if err != nil {
+		return nil, err
+	}
+	s := &datagramLogStream{
+		conn:  conn,
+		lines: make(chan Line),
+		stop:  make(chan struct{}),
+		done:  make(chan struct{}),
+	}
+	go s.run()
+	return s, nil
+}
+
+func (s *datagramLogStream) Lines() <-chan Line { return s.lines }
+
+func (s *datagramLogStream) Close() error {
+	s.closeOnce.Do(func() {
+		_ = s.conn.Close()
+		close(s.stop)
+	})
+	<-s.done
+	return nil
+}
+
+func (s *datagramLogStream) run() {
+	defer close(s.done)
+	defer close(s.lines)
+	buffer := make([]byte, 64*1024)
+	for {
+		n, _, err := s.conn.ReadFrom(buffer)
+		if err != nil {
+			select {
+			case <-s.stop:
+				return
+			default:
+			}
+			select {
+			case s.lines <- Line{Err: err, Time: time.Now()}:
+			case <-s.stop:
+			}
+			return
+		}
+		s.lineNum++
+		text := string(buffer[:n])
+		line := Line{Text: text, Bytes: []byte(text), LineNum: s.lineNum, Time: time.Now()}
+		select {
+		case s.lines <- line:
+		case <-s.stop:
+			return
+		}
+	}
+}
+
+// streamLogStream is a LogStream backed by a listening stream socket (TCP or Unix): every accepted
+// connection is read as a newline-delimited sequence of Lines via LineReader, as if each were its own
+// tailed file.
+type streamLogStream struct {
+	listener net.Listener
+	lines    chan Line
+
+	connsMu sync.Mutex
+	conns   map[net.Conn]struct{}
+	wg      sync.WaitGroup
+
+	stop      chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// newStreamLogStream starts listening on network/address (as accepted by net.Listen) and begins
+// delivering newline-delimited Lines read from every accepted connection.
+func newStreamLogStream(network, address string) (*streamLogStream, error) {
+	listener, err := net.Listen(network, address)
+	if err != nil {
+		return nil, err
+	}
+	s := &streamLogStream{
+		listener: listener,
+		lines:    make(chan Line),
+		conns:    make(map[net.Conn]struct{}),
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	go s.acceptLoop()
+	return s, nil
+}
+
+func (s *streamLogStream) Lines() <-chan Line { return s.lines }
+
+func (s *streamLogStream) Close() error {
+	s.closeOnce.Do(func() {
+		_ = s.listener.Close()
+		close(s.stop)
+		s.connsMu.Lock()
+		for conn := range s.conns {
+			_ = conn.Close()
+		}
+		s.connsMu.Unlock()
+	})
+	<-s.done
+	return nil
+}
+
+func (s *streamLogStream) acceptLoop() {
+	defer close(s.done)
+	defer close(s.lines)
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			s.wg.Wait()
+			select {
+			case <-s.stop:
+			default:
+				select {
+				case s.lines <- Line{Err: err, Time: time.Now()}:
+				case <-s.stop:
+				}
+			}
+			return
+		}
+		s.connsMu.Lock()
+		s.conns[conn] = struct{}{}
+		s.connsMu.Unlock()
+		s.wg.Add(1)
+		go s.handleConn(conn)
+	}
+}
+
+func (s *streamLogStream) handleConn(conn net.Conn) {
+	defer s.wg.Done()
+	defer func() {
+		s.connsMu.Lock()
+		delete(s.conns, conn)
+		s.connsMu.Unlock()
+		_ = conn.Close()
+	}()
+	reader := NewLineReader(conn)
+	lineNum := 0
+	for {
+		line, _, err := reader.ReadLine()
+		if err != nil {
+			return
+		}
+		lineNum++
+		select {
+		case s.lines <- Line{Text: string(line), Bytes: line, LineNum: lineNum, Time: time.Now()}:
+		case <-s.stop:
+			return
+		}
+	}
+}
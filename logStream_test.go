@@ -0,0 +1,121 @@
+package logReader
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func readStreamLine(t *testing.T, lines <-chan Line) Line {
+	t.Helper()
+	select {
+	case line := <-lines:
+		return line
+	case <-time.After(2 * time.Second):
+		t.Fatal("didn't get an expected line from the stream")
+		return Line{}
+	}
+}
+
+func TestDatagramLogStreamReadsOnePacketPerLine(t *testing.T) {
+	stream, err := newDatagramLogStream("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stream.Close()
+
+	conn, err := net.Dial("udp", stream.conn.LocalAddr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	if _, err = conn.Write([]byte("hello")); err != nil {
+		t.Fatal(err)
+	}
+
+	if line := readStreamLine(t, stream.Lines()); line.Text != "hello" {
+		t.Errorf("expected 'hello', got %q", line.Text)
+	}
+}
+
+func TestStreamLogStreamReadsNewlineDelimitedLines(t *testing.T) {
+	stream, err := newStreamLogStream("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stream.Close()
+
+	conn, err := net.Dial("tcp", stream.listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	if _, err = conn.Write([]byte("line one\nline two\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	if line := readStreamLine(t, stream.Lines()); line.Text != "line one" {
+		t.Errorf("expected 'line one', got %q", line.Text)
+	}
+	if line := readStreamLine(t, stream.Lines()); line.Text != "line two" {
+		t.Errorf("expected 'line two', got %q", line.Text)
+	}
+}
+
+func TestStreamLogStreamCloseStopsAcceptingAndClosesConnections(t *testing.T) {
+	stream, err := newStreamLogStream("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	conn, err := net.Dial("tcp", stream.listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	if err = stream.Close(); err != nil {
+		t.Errorf("expected a clean Close, got %v", err)
+	}
+	if _, ok := <-stream.Lines(); ok {
+		t.Errorf("expected Lines() to be closed after Close returns")
+	}
+}
+
+func TestOpenDispatchesFileScheme(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "log.txt")
+	if err := os.WriteFile(path, []byte("hello\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	stream, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stream.Close()
+	if line := readStreamLine(t, stream.Lines()); line.Text != "hello" {
+		t.Errorf("expected 'hello', got %q", line.Text)
+	}
+}
+
+func TestOpenDispatchesSocketSchemes(t *testing.T) {
+	udpStream, err := Open("udp://127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer udpStream.Close()
+
+	tcpStream, err := Open("tcp://127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tcpStream.Close()
+}
+
+func TestOpenRejectsUnsupportedScheme(t *testing.T) {
+	if _, err := Open("bogus://somewhere"); err == nil {
+		t.Error("expected an error for an unsupported scheme")
+	}
+}
@@ -0,0 +1,147 @@
+package logReader
+
+import (
+	"bytes"
+	"io"
+	"regexp"
+	"time"
+)
+
+// MatchMode selects how MultilineConfig.Pattern groups physical lines into a logical record.
+type MatchMode int
+
+const (
+	// After groups a line into the current record when Pattern matches that line, e.g. the indented
+	// continuation lines of a Java stack trace (`^\s`).
+	After MatchMode = iota
+	// Before groups a line into the current record when Pattern matched the previous line, e.g. lines
+	// that signal a continuation follows by ending in a trailing backslash.
+	Before
+)
+
+// MultilineConfig configures a MultilineReader.
+type MultilineConfig struct {
+	// Pattern decides, together with Match, whether a line continues the current record or starts a new
+	// one.
+	Pattern *regexp.Regexp
+	// Match selects how Pattern is interpreted. Its zero value is After.
+	Match MatchMode
+	// MaxLines caps how many physical lines a record can hold before it's flushed regardless of Pattern.
+	// Zero, the default, means unlimited.
+	MaxLines int
+	// FlushTimeout flushes a record that has been accumulating for at least this long once ReadRecord finds
+	// no further lines available, rather than holding it back waiting for a boundary. Zero, the default,
+	// means records are only flushed by a Pattern boundary or MaxLines.
+	FlushTimeout time.Duration
+}
+
+// MultilineReader wraps a LineReader, joining consecutive physical lines into a single logical record
+// following MultilineConfig, the way Filebeat's and Fluent Bit's multiline processors do. This is commonly
+// used to keep a stack trace or traceback attached to the log line that introduced it.
+type MultilineReader struct {
+	lr     *LineReader
+	config MultilineConfig
+
+	pending       [][]byte
+	pendingOffset int64
+	pendingSince  time.Time
+	lastMatched   bool
+
+	heldLine   []byte
+	heldOffset int64
+	hasHeld    bool
+
+	finalized bool
+}
+
+// NewMultilineReader makes a MultilineReader that joins lines read from lr following config.
+func NewMultilineReader(lr *LineReader, config MultilineConfig) *MultilineReader {
+	return &MultilineReader{lr: lr, config: config}
+}
+
+// Finalize finalizes the wrapped LineReader (see LineReader.Finalize) and additionally tells
+// MultilineReader not to wait for a boundary line once that happens: a record that's still accumulating is
+// flushed as soon as no further lines are available, instead of waiting indefinitely.
+func (m *MultilineReader) Finalize() {
+	m.lr.Finalize()
+	m.finalized = true
+}
+
+// ReadRecord returns the next joined record, following the same (record, position, error) contract as
+// LineReader.ReadLine. The returned position is the one LineReader.ReadLine reported right after the
+// record's first line, matching how Filebeat anchors a multi-line event to its first line.
+func (m *MultilineReader) ReadRecord() ([]byte, int64, error) {
+	for {
+		line, offset, err := m.nextLine()
+		if err == io.EOF && line != nil {
+			// A finalized LineReader returns its unterminated last line together with io.EOF. Take it like
+			// any other line; the next read reports io.EOF on its own.
+			err = nil
+		}
+		if err != nil {
+			if err == io.EOF && len(m.pending) > 0 && m.readyToFlushOnEOF() {
+				return m.flush()
+			}
+			return nil, 0, err
+		}
+
+		if len(m.pending) > 0 && !m.continuesRecord(line) {
+			m.hold(line, offset)
+			return m.flush()
+		}
+
+		m.append(line, offset)
+		if m.config.MaxLines > 0 && len(m.pending) >= m.config.MaxLines {
+			return m.flush()
+		}
+	}
+}
+
+// nextLine returns the next line to consider, preferring one held back by a previous call once it turned
+// out to start a new record rather than continue the one just flushed.
+func (m *MultilineReader) nextLine() ([]byte, int64, error) {
+	if m.hasHeld {
+		m.hasHeld = false
+		return m.heldLine, m.heldOffset, nil
+	}
+	return m.lr.ReadLine()
+}
+
+// readyToFlushOnEOF reports whether a pending record should be flushed now that no further lines are
+// available, rather than held back waiting for a boundary line.
+func (m *MultilineReader) readyToFlushOnEOF() bool {
+	if m.finalized {
+		return true
+	}
+	return m.config.FlushTimeout > 0 && time.Since(m.pendingSince) >= m.config.FlushTimeout
+}
+
+func (m *MultilineReader) hold(line []byte, offset int64) {
+	m.heldLine, m.heldOffset, m.hasHeld = line, offset, true
+}
+
+// continuesRecord reports whether line should be appended to the record being accumulated. It's only
+// consulted once a record is already underway; the first line of any record is always accepted.
+func (m *MultilineReader) continuesRecord(line []byte) bool {
+	if m.config.Match == Before {
+		return m.lastMatched
+	}
+	return m.config.Pattern.Match(line)
+}
+
+func (m *MultilineReader) append(line []byte, offset int64) {
+	if len(m.pending) == 0 {
+		m.pendingOffset = offset
+		m.pendingSince = time.Now()
+	}
+	m.pending = append(m.pending, line)
+	m.lastMatched = m.config.Pattern.Match(line)
+}
+
+func (m *MultilineReader) flush() ([]byte, int64, error) {
+	record := bytes.Join(m.pending, []byte("\n"))
+	offset := m.pendingOffset
+	m.pending = nil
+	m.lastMatched = false
+	return record, offset, nil
+}
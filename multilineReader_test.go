@@ -0,0 +1,93 @@
+package logReader
+
+import (
+	"io"
+	"regexp"
+	"testing"
+	"time"
+)
+
+// assertReadRecordFindsRecord ensures that calling ReadRecord() once will find the expected record.
+func assertReadRecordFindsRecord(t *testing.T, mr *MultilineReader, expected string) {
+	t.Helper()
+	record, _, err := mr.ReadRecord()
+	if err != nil {
+		t.Error(err)
+	}
+	if string(record) != expected {
+		t.Errorf("Expected to find record:\n%s\nBut found\n%s", expected, record)
+	}
+}
+
+// Test that indented continuation lines (e.g. a Java stack trace) are joined onto the preceding record.
+func TestMultilineJoinsIndentedContinuationLines(t *testing.T) {
+	ft := newFileForTest(t)
+	ft.Append("2020-01-01 first\n  at foo\n  at bar\n2020-01-01 second\n")
+	mr := NewMultilineReader(NewLineReader(ft.Reader), MultilineConfig{Pattern: regexp.MustCompile(`^\s`), Match: After})
+
+	assertReadRecordFindsRecord(t, mr, "2020-01-01 first\n  at foo\n  at bar")
+	mr.Finalize()
+	assertReadRecordFindsRecord(t, mr, "2020-01-01 second")
+}
+
+// Test that a record still unterminated at EOF is held back, like an unterminated LineReader line.
+func TestMultilineWaitsAtEOFForMoreContinuationLines(t *testing.T) {
+	ft := newFileForTest(t)
+	ft.Append("first\n  at foo\n")
+	mr := NewMultilineReader(NewLineReader(ft.Reader), MultilineConfig{Pattern: regexp.MustCompile(`^\s`), Match: After})
+
+	_, _, err := mr.ReadRecord()
+	if err != io.EOF {
+		t.Errorf("expected io.EOF while the record might still grow, got %v", err)
+	}
+
+	ft.Append("  at bar\nsecond\n")
+	assertReadRecordFindsRecord(t, mr, "first\n  at foo\n  at bar")
+	mr.Finalize()
+	assertReadRecordFindsRecord(t, mr, "second")
+}
+
+// Test Match: Before, where the pattern marks the line that introduces a continuation (e.g. a trailing
+// backslash), rather than the continuation line itself.
+func TestMultilineMatchBeforeGroupsByPrecedingLine(t *testing.T) {
+	ft := newFileForTest(t)
+	ft.Append("one \\\ntwo\nthree\n")
+	mr := NewMultilineReader(NewLineReader(ft.Reader), MultilineConfig{Pattern: regexp.MustCompile(`\\$`), Match: Before})
+
+	assertReadRecordFindsRecord(t, mr, "one \\\ntwo")
+	mr.Finalize()
+	assertReadRecordFindsRecord(t, mr, "three")
+}
+
+// Test that MaxLines flushes a record even though the pattern would otherwise keep growing it.
+func TestMultilineFlushesAtMaxLines(t *testing.T) {
+	ft := newFileForTest(t)
+	ft.Append("first\n  a\n  b\n  c\n")
+	mr := NewMultilineReader(NewLineReader(ft.Reader), MultilineConfig{
+		Pattern:  regexp.MustCompile(`^\s`),
+		Match:    After,
+		MaxLines: 2,
+	})
+
+	assertReadRecordFindsRecord(t, mr, "first\n  a")
+	assertReadRecordFindsRecord(t, mr, "  b\n  c")
+}
+
+// Test that FlushTimeout flushes a pending record once it's been accumulating long enough, instead of
+// waiting indefinitely for a boundary line or EOF to go away.
+func TestMultilineFlushesOnTimeout(t *testing.T) {
+	ft := newFileForTest(t)
+	ft.Append("first\n  a\n")
+	mr := NewMultilineReader(NewLineReader(ft.Reader), MultilineConfig{
+		Pattern:      regexp.MustCompile(`^\s`),
+		Match:        After,
+		FlushTimeout: 5 * time.Millisecond,
+	})
+
+	if _, _, err := mr.ReadRecord(); err != io.EOF {
+		t.Errorf("expected io.EOF before the timeout elapses, got %v", err)
+	}
+
+	time.Sleep(10 * time.Millisecond)
+	assertReadRecordFindsRecord(t, mr, "first\n  a")
+}
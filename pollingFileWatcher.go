@@ -3,6 +3,7 @@ package logReader
 import (
 	"context"
 	"github.com/spf13/afero"
+	"os"
 	"time"
 )
 
@@ -10,6 +11,11 @@ type PollingFileWatcher struct {
 	filename string
 	fs       afero.Fs
 	err      error
+
+	// ReOpen makes the watcher tolerate the watched file disappearing, e.g. renamed away as part of a
+	// rename-and-recreate log rotation, by waiting for it to reappear instead of treating its absence as
+	// a fatal error. Once it reappears, the change is reported as Rotated.
+	ReOpen bool
 }
 
 var _ FileWatcher = &PollingFileWatcher{}
@@ -18,6 +24,12 @@ func NewOsPollingFileWatcher(filename string) *PollingFileWatcher {
 	return &PollingFileWatcher{filename: filename, fs: afero.NewOsFs()}
 }
 
+// NewReOpeningPollingFileWatcher is like NewOsPollingFileWatcher, but keeps watching across the file
+// being renamed away and recreated under the same name, as happens with rename-and-recreate log rotation.
+func NewReOpeningPollingFileWatcher(filename string) *PollingFileWatcher {
+	return &PollingFileWatcher{filename: filename, fs: afero.NewOsFs(), ReOpen: true}
+}
+
 func (pw *PollingFileWatcher) Start() (<-chan UpdateSignal, func()) {
 	updates := make(chan UpdateSignal)
 	ctx, cancel := context.WithCancel(context.Background())
@@ -28,28 +40,45 @@ func (pw *PollingFileWatcher) Start() (<-chan UpdateSignal, func()) {
 const pollInterval = 10 * time.Millisecond
 const refreshInterval = 1 * time.Second
 
-var updateSignal UpdateSignal = struct{}{}
-
 func (pw *PollingFileWatcher) watch(ctx context.Context, updates chan UpdateSignal) {
 	var lastSize int64 = 0
 	lastModTime := time.Unix(0, 0)
+	reappeared := false
 	for {
 		fileInfo, err := pw.fs.Stat(pw.filename)
 		if err != nil {
+			if pw.ReOpen && os.IsNotExist(err) {
+				reappeared = true
+				select {
+				case <-time.After(pollInterval):
+					continue
+				case <-ctx.Done():
+					close(updates)
+					return
+				}
+			}
 			pw.err = err
 			close(updates)
 			return
 		}
 		currentSize := fileInfo.Size()
 		currentModTime := fileInfo.ModTime()
-		if currentSize != lastSize || !currentModTime.Equal(lastModTime) {
+		if reappeared || currentSize != lastSize || !currentModTime.Equal(lastModTime) {
+			kind := Appended
+			switch {
+			case reappeared:
+				kind = Rotated
+			case currentSize < lastSize:
+				kind = Truncated
+			}
 			// if we're blocked for too long, should we refresh lastSize/lastModTime?
 			select {
-			case updates <- updateSignal:
+			case updates <- UpdateSignal{Kind: kind}:
 				// Notice that we update lastSize and ModTIme after we sent the notification.
 				// This is needed in order for refreshInterval to work.
 				lastSize = currentSize
 				lastModTime = currentModTime
+				reappeared = false
 			case <-ctx.Done():
 				close(updates)
 				return
@@ -35,6 +35,83 @@ func TestNotifiesOnAppend(t *testing.T) {
 	}
 }
 
+func TestSignalsTruncated(t *testing.T) {
+	file, err := TestFs.OpenFile(t.Name(), syscall.O_CREAT|syscall.O_APPEND|syscall.O_SYNC, 0600)
+	if err != nil {
+		t.Error(err)
+	}
+	if _, err = fmt.Fprintln(file, "Hello World"); err != nil {
+		t.Error(err)
+	}
+	watcher := PollingFileWatcher{filename: t.Name(), fs: TestFs}
+	updates, stop := watcher.Start()
+	defer stop()
+	select {
+	case signal := <-updates:
+		if signal.Kind != Appended {
+			t.Errorf("expected initial signal to be Appended, got %v", signal.Kind)
+		}
+	case <-time.After(10 * pollInterval):
+		t.Errorf("didn't get initial update from file watcher")
+	}
+	truncated, err := TestFs.OpenFile(t.Name(), syscall.O_WRONLY|syscall.O_TRUNC, 0600)
+	if err != nil {
+		t.Error(err)
+	}
+	if err = truncated.Close(); err != nil {
+		t.Error(err)
+	}
+	select {
+	case signal := <-updates:
+		if signal.Kind != Truncated {
+			t.Errorf("expected Truncated, got %v", signal.Kind)
+		}
+	case <-time.After(10 * pollInterval):
+		t.Errorf("watcher didn't signal the truncation.")
+	}
+}
+
+func TestSignalsRotatedWhenReOpening(t *testing.T) {
+	if _, err := TestFs.OpenFile(t.Name(), syscall.O_CREAT|syscall.O_APPEND|syscall.O_SYNC, 0600); err != nil {
+		t.Error(err)
+	}
+	watcher := PollingFileWatcher{filename: t.Name(), fs: TestFs, ReOpen: true}
+	updates, stop := watcher.Start()
+	defer stop()
+	select {
+	case <-updates:
+	case <-time.After(10 * pollInterval):
+		t.Errorf("didn't get initial update")
+	}
+	if err := TestFs.Remove(t.Name()); err != nil {
+		t.Error(err)
+	}
+	select {
+	case _, ok := <-updates:
+		if !ok {
+			t.Errorf("closed the channel while the file was only temporarily missing")
+		} else {
+			t.Errorf("got an unexpected update while the file was missing")
+		}
+	case <-time.After(10 * pollInterval):
+		// expected: no update while the file is missing, and the channel stays open.
+	}
+	if _, err := TestFs.OpenFile(t.Name(), syscall.O_CREAT|syscall.O_APPEND|syscall.O_SYNC, 0600); err != nil {
+		t.Error(err)
+	}
+	select {
+	case signal, ok := <-updates:
+		if !ok {
+			t.Errorf("channel closed unexpectedly")
+		}
+		if signal.Kind != Rotated {
+			t.Errorf("expected Rotated, got %v", signal.Kind)
+		}
+	case <-time.After(10 * pollInterval):
+		t.Errorf("watcher didn't signal the file reappearing.")
+	}
+}
+
 func TestClosesAndStoresErrorIfStatFails(t *testing.T) {
 	_, err := TestFs.OpenFile(t.Name(), syscall.O_CREAT|syscall.O_APPEND|syscall.O_SYNC, 0600)
 	if err != nil {
@@ -0,0 +1,51 @@
+package logReader
+
+import "time"
+
+// leakyBucket is a token-bucket rate limiter: tokens refill continuously, up to burst, at rate tokens per
+// second; each unit of work consumes one. It's meant to be driven by a single goroutine; it keeps no
+// internal locking.
+type leakyBucket struct {
+	rate  float64
+	burst float64
+
+	tokens float64
+	last   time.Time
+}
+
+// newLeakyBucket makes a leakyBucket that starts full, allowing an initial burst of up to burst units of
+// work before rate limiting kicks in.
+func newLeakyBucket(rate float64, burst int) *leakyBucket {
+	return &leakyBucket{rate: rate, burst: float64(burst), tokens: float64(burst), last: time.Now()}
+}
+
+func (b *leakyBucket) refill() {
+	now := time.Now()
+	b.tokens += now.Sub(b.last).Seconds() * b.rate
+	if b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+	b.last = now
+}
+
+// TryTake attempts to consume a token without waiting, returning whether one was available.
+func (b *leakyBucket) TryTake() bool {
+	b.refill()
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// Reserve consumes a token and returns how long the caller should wait before acting on it, 0 if one was
+// immediately available. It never sleeps itself, so the wait is interruptible at the caller's discretion.
+func (b *leakyBucket) Reserve() time.Duration {
+	b.refill()
+	// An empty bucket goes into debt, so the token the caller waits for isn't handed out again.
+	b.tokens--
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
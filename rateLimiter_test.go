@@ -0,0 +1,43 @@
+package logReader
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLeakyBucketAllowsBurstThenLimits(t *testing.T) {
+	b := newLeakyBucket(10, 2)
+	if !b.TryTake() {
+		t.Errorf("expected the first token of the burst to be available")
+	}
+	if !b.TryTake() {
+		t.Errorf("expected the second token of the burst to be available")
+	}
+	if b.TryTake() {
+		t.Errorf("expected the burst to be exhausted")
+	}
+}
+
+func TestLeakyBucketRefillsOverTime(t *testing.T) {
+	b := newLeakyBucket(1000, 1)
+	if !b.TryTake() {
+		t.Errorf("expected the initial token to be available")
+	}
+	if b.TryTake() {
+		t.Errorf("expected no token immediately after exhausting the bucket")
+	}
+	time.Sleep(5 * time.Millisecond)
+	if !b.TryTake() {
+		t.Errorf("expected a token to have refilled after waiting")
+	}
+}
+
+func TestLeakyBucketReserveReturnsWaitWhenEmpty(t *testing.T) {
+	b := newLeakyBucket(10, 1)
+	if wait := b.Reserve(); wait != 0 {
+		t.Errorf("expected no wait for the first reservation, got %v", wait)
+	}
+	if wait := b.Reserve(); wait <= 0 {
+		t.Errorf("expected a positive wait once the bucket is empty, got %v", wait)
+	}
+}
@@ -0,0 +1,277 @@
+package logReader
+
+import (
+	"context"
+	"errors"
+	"github.com/spf13/afero"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Line is a single line read by a Tail. If Err is non-nil, it's the final value sent on a Tail's Lines()
+// channel before it closes, and the other fields are meaningless.
+//
+// Continued is set when the physical line exceeded TailConfig.MaxLineSize: Text/Bytes hold only a chunk of
+// it, and the next Line on the channel continues it rather than starting a new one.
+type Line struct {
+	Text      string
+	Bytes     []byte
+	Offset    int64
+	LineNum   int
+	Time      time.Time
+	Continued bool
+	Err       error
+}
+
+// TailConfig configures a Tail.
+type TailConfig struct {
+	// ReOpen makes Tail transparently reopen the watched file, resuming from its start, when it is
+	// truncated or rotated, instead of surfacing the condition as an error on Lines().
+	ReOpen bool
+
+	// MaxLinesPerSecond caps the rate at which lines are sent on Lines(). Zero (the default) means no
+	// limit.
+	MaxLinesPerSecond float64
+	// Burst is the largest number of lines Tail will emit back-to-back before MaxLinesPerSecond starts
+	// throttling. It defaults to 1 when MaxLinesPerSecond is set and Burst is left at 0.
+	Burst int
+	// DropWhenLimited makes Tail drop lines that exceed the rate limit instead of blocking emission until
+	// one is available. Dropped lines are counted; see Tail.Dropped.
+	DropWhenLimited bool
+
+	// MaxLineSize caps how large a single physical line is allowed to grow before Tail splits it into
+	// several Lines, each with Continued set except the last. Zero, the default, means unlimited. See
+	// LineReaderOptions.MaxLineSize.
+	MaxLineSize int
+}
+
+// ErrFileChanged is sent as a Line.Err when the Tail was not configured to ReOpen and its FileWatcher
+// reports the watched file Truncated or Rotated, or a read fails with io.ErrUnexpectedEOF because the file
+// shrank. Without ReOpen, PollingFileWatcher reports a shrunken file as Truncated, while a file renamed
+// away ends the Tail with the watcher's stat error instead. FsNotifyFileWatcher only reports Appended, so
+// it does not detect a truncation or rotation.
+var ErrFileChanged = errors.New("watched file was truncated or rotated")
+
+// Tail follows a file similarly to `tail -F`: it owns a FileWatcher and a LineReader for filename, and
+// makes already-written and subsequently appended lines available through Lines().
+type Tail struct {
+	filename string
+	fs       afero.Fs
+	config   TailConfig
+
+	file          afero.File
+	lineReader    *LineReader
+	watcher       FileWatcher
+	updates       <-chan UpdateSignal
+	watcherCancel func()
+
+	lines   chan Line
+	lineNum int
+	err     error
+	limiter *leakyBucket
+	dropped int64
+
+	stop      chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// NewTail starts tailing filename from position on the OS filesystem, using poll to pick between a
+// PollingFileWatcher and an FsNotifyFileWatcher. See NewFileWatcher.
+//
+// config.ReOpen forces polling regardless of poll: FsNotifyFileWatcher only ever reports Appended, since
+// translating the underlying fsnotify events into Truncated/Rotated isn't implemented, so it can't be used
+// to detect the rotations ReOpen needs to recover from.
+func NewTail(filename string, position int64, poll bool, config TailConfig) (*Tail, error) {
+	fs := afero.NewOsFs()
+	var watcher FileWatcher
+	if config.ReOpen {
+		watcher = NewReOpeningPollingFileWatcher(filename)
+	} else {
+		watcher = NewFileWatcher(filename, poll)
+	}
+	return newTail(fs, filename, position, watcher, config)
+}
+
+// newTail wires a Tail from an already-built FileWatcher against an arbitrary afero.Fs, so it can be
+// exercised against an in-memory filesystem in tests.
+func newTail(fs afero.Fs, filename string, position int64, watcher FileWatcher, config TailConfig) (*Tail, error) {
+	file, err := fs.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	lineReader, err := NewLineReaderAtPositionWithOptions(file, position, false, LineReaderOptions{MaxLineSize: config.MaxLineSize})
+	if err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+
+	t := &Tail{
+		filename:   filename,
+		fs:         fs,
+		config:     config,
+		file:       file,
+		lineReader: lineReader,
+		watcher:    watcher,
+		lines:      make(chan Line),
+		stop:       make(chan struct{}),
+		done:       make(chan struct{}),
+	}
+	if config.MaxLinesPerSecond > 0 {
+		burst := config.Burst
+		if burst == 0 {
+			burst = 1
+		}
+		t.limiter = newLeakyBucket(config.MaxLinesPerSecond, burst)
+	}
+	t.updates, t.watcherCancel = watcher.Start()
+	go t.run()
+	return t, nil
+}
+
+// Lines returns the channel lines read from the tailed file are sent on. Its last value has a non-nil Err
+// if the Tail stopped because of an unrecoverable error; the channel is closed right after. It's also
+// closed, without a final error Line, once Close returns nil.
+func (t *Tail) Lines() <-chan Line {
+	return t.lines
+}
+
+// Err returns the error that caused Lines() to close, if any. It's only meaningful after Lines() is
+// closed.
+func (t *Tail) Err() error {
+	return t.err
+}
+
+// Dropped returns the number of lines discarded so far because they exceeded the rate limit configured
+// via TailConfig.DropWhenLimited. It's always zero unless DropWhenLimited is set.
+func (t *Tail) Dropped() int64 {
+	return atomic.LoadInt64(&t.dropped)
+}
+
+// Close stops the Tail and releases its resources, waiting for its internal goroutine to exit or ctx to
+// be done, whichever comes first. It's safe to call multiple times.
+func (t *Tail) Close(ctx context.Context) error {
+	t.closeOnce.Do(func() {
+		close(t.stop)
+	})
+	select {
+	case <-t.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (t *Tail) run() {
+	defer close(t.done)
+	defer close(t.lines)
+	defer t.watcherCancel()
+	defer t.file.Close()
+	for {
+		line, offset, err := t.lineReader.ReadLine()
+		if err == nil || err == ErrLineTooLong {
+			t.lineNum++
+			l := Line{Text: string(line), Bytes: line, Offset: offset, LineNum: t.lineNum, Time: time.Now(), Continued: err == ErrLineTooLong}
+			if !t.emit(l) {
+				return
+			}
+			continue
+		}
+		if err == io.ErrUnexpectedEOF {
+			// The file shrank while we were reading it, which some filesystems surface directly as a
+			// read error rather than waiting for the next watcher signal. Handle it the same way as a
+			// Truncated signal.
+			if !t.handleFileChanged() {
+				return
+			}
+			continue
+		}
+		if err != io.EOF {
+			t.finish(err)
+			return
+		}
+		select {
+		case signal, ok := <-t.updates:
+			if !ok {
+				t.finish(t.watcher.Err())
+				return
+			}
+			if signal.Kind == Truncated || signal.Kind == Rotated {
+				if !t.handleFileChanged() {
+					return
+				}
+			}
+		case <-t.stop:
+			return
+		}
+	}
+}
+
+// handleFileChanged reopens the file if the Tail is configured to, or finishes it with ErrFileChanged
+// otherwise. It returns whether the Tail should keep running.
+func (t *Tail) handleFileChanged() bool {
+	if !t.config.ReOpen {
+		t.finish(ErrFileChanged)
+		return false
+	}
+	if err := t.reopen(); err != nil {
+		t.finish(err)
+		return false
+	}
+	return true
+}
+
+// emit delivers a successfully read line, applying the configured rate limit first. It returns false if
+// the Tail was closed before the line could be sent (or while waiting for rate-limiting capacity).
+func (t *Tail) emit(line Line) bool {
+	if t.limiter != nil {
+		if t.config.DropWhenLimited {
+			if !t.limiter.TryTake() {
+				atomic.AddInt64(&t.dropped, 1)
+				return true
+			}
+		} else if wait := t.limiter.Reserve(); wait > 0 {
+			select {
+			case <-time.After(wait):
+			case <-t.stop:
+				return false
+			}
+		}
+	}
+	select {
+	case t.lines <- line:
+		return true
+	case <-t.stop:
+		return false
+	}
+}
+
+// finish records err and, best-effort, delivers it as a final Line, mirroring how hpcloud/nxadm tail
+// surfaces errors.
+func (t *Tail) finish(err error) {
+	t.err = err
+	select {
+	case t.lines <- Line{Err: err, Time: time.Now()}:
+	case <-t.stop:
+	}
+}
+
+// reopen reopens the tailed file from the beginning, as needed after a truncation or rotation.
+func (t *Tail) reopen() error {
+	file, err := t.fs.Open(t.filename)
+	if err != nil {
+		return err
+	}
+	lineReader, err := NewLineReaderAtPositionWithOptions(file, 0, false, LineReaderOptions{MaxLineSize: t.config.MaxLineSize})
+	if err != nil {
+		_ = file.Close()
+		return err
+	}
+	_ = t.file.Close()
+	t.file = file
+	t.lineReader = lineReader
+	t.lineNum = 0
+	return nil
+}
@@ -0,0 +1,274 @@
+package logReader
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func readLine(t *testing.T, lines <-chan Line) Line {
+	t.Helper()
+	select {
+	case line := <-lines:
+		return line
+	case <-time.After(10 * pollInterval):
+		t.Fatal("didn't get an expected line from Tail")
+		return Line{}
+	}
+}
+
+func TestTailBlocksEmissionWhenRateLimited(t *testing.T) {
+	file, err := TestFs.OpenFile(t.Name(), syscall.O_CREAT|syscall.O_APPEND|syscall.O_SYNC, 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 3; i++ {
+		if _, err = fmt.Fprintln(file, "line"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	watcher := &PollingFileWatcher{filename: t.Name(), fs: TestFs}
+	tail, err := newTail(TestFs, t.Name(), 0, watcher, TailConfig{MaxLinesPerSecond: 20, Burst: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tail.Close(context.Background())
+
+	readLine(t, tail.Lines())
+	select {
+	case <-tail.Lines():
+		t.Errorf("expected the second line to be held back by the rate limit")
+	case <-time.After(pollInterval):
+	}
+	readLine(t, tail.Lines())
+	if tail.Dropped() != 0 {
+		t.Errorf("expected no dropped lines while blocking, got %d", tail.Dropped())
+	}
+}
+
+func TestTailDropsLinesWhenConfiguredTo(t *testing.T) {
+	file, err := TestFs.OpenFile(t.Name(), syscall.O_CREAT|syscall.O_APPEND|syscall.O_SYNC, 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 3; i++ {
+		if _, err = fmt.Fprintln(file, "line"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	watcher := &PollingFileWatcher{filename: t.Name(), fs: TestFs}
+	tail, err := newTail(TestFs, t.Name(), 0, watcher, TailConfig{
+		MaxLinesPerSecond: 1000,
+		Burst:             1,
+		DropWhenLimited:   true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tail.Close(context.Background())
+
+	readLine(t, tail.Lines())
+	select {
+	case line := <-tail.Lines():
+		t.Errorf("expected the remaining lines to be dropped, got %q", line.Text)
+	case <-time.After(10 * pollInterval):
+	}
+	if tail.Dropped() != 2 {
+		t.Errorf("expected 2 dropped lines, got %d", tail.Dropped())
+	}
+}
+
+func TestTailSplitsLinesExceedingMaxLineSize(t *testing.T) {
+	const totalLen = 120
+	file, err := TestFs.OpenFile(t.Name(), syscall.O_CREAT|syscall.O_APPEND|syscall.O_SYNC, 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = fmt.Fprintln(file, strings.Repeat("x", totalLen)); err != nil {
+		t.Fatal(err)
+	}
+
+	watcher := &PollingFileWatcher{filename: t.Name(), fs: TestFs}
+	tail, err := newTail(TestFs, t.Name(), 0, watcher, TailConfig{MaxLineSize: 100})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tail.Close(context.Background())
+
+	first := readLine(t, tail.Lines())
+	if !first.Continued || first.Text != strings.Repeat("x", 100) {
+		t.Errorf("expected a 100 byte continued chunk, got Continued=%v len=%d", first.Continued, len(first.Text))
+	}
+	second := readLine(t, tail.Lines())
+	if second.Continued || second.Text != strings.Repeat("x", 20) {
+		t.Errorf("expected the remaining 20 bytes to end the line, got Continued=%v len=%d", second.Continued, len(second.Text))
+	}
+}
+
+// Test that NewTail itself, not just newTail wired up by hand, honors ReOpen. poll is left false (the CLI
+// default) to make sure ReOpen doesn't silently depend on it: NewTail must pick a watcher that can actually
+// detect the truncation regardless.
+func TestNewTailForcesPollingWhenReOpening(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "log.txt")
+	if err := os.WriteFile(path, []byte("before truncate\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	tail, err := NewTail(path, 0, false, TailConfig{ReOpen: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tail.Close(context.Background())
+
+	if line := readLine(t, tail.Lines()); line.Text != "before truncate" {
+		t.Errorf("expected 'before truncate', got %q", line.Text)
+	}
+
+	if err = os.WriteFile(path, []byte("after truncate\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if line := readLine(t, tail.Lines()); line.Text != "after truncate" {
+		t.Errorf("expected 'after truncate', got %q", line.Text)
+	}
+}
+
+func TestTailReopensOnTruncate(t *testing.T) {
+	file, err := TestFs.OpenFile(t.Name(), syscall.O_CREAT|syscall.O_APPEND|syscall.O_SYNC, 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = fmt.Fprintln(file, "before truncate"); err != nil {
+		t.Fatal(err)
+	}
+
+	watcher := &PollingFileWatcher{filename: t.Name(), fs: TestFs, ReOpen: true}
+	tail, err := newTail(TestFs, t.Name(), 0, watcher, TailConfig{ReOpen: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tail.Close(context.Background())
+
+	if line := readLine(t, tail.Lines()); string(line.Text) != "before truncate" {
+		t.Errorf("expected 'before truncate', got %q", line.Text)
+	}
+
+	truncated, err := TestFs.OpenFile(t.Name(), syscall.O_WRONLY|syscall.O_TRUNC, 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = fmt.Fprintln(truncated, "after truncate"); err != nil {
+		t.Fatal(err)
+	}
+	if err = truncated.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if line := readLine(t, tail.Lines()); string(line.Text) != "after truncate" {
+		t.Errorf("expected 'after truncate', got %q", line.Text)
+	}
+}
+
+func TestTailReopensOnRename(t *testing.T) {
+	if _, err := TestFs.OpenFile(t.Name(), syscall.O_CREAT|syscall.O_APPEND|syscall.O_SYNC, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	watcher := &PollingFileWatcher{filename: t.Name(), fs: TestFs, ReOpen: true}
+	tail, err := newTail(TestFs, t.Name(), 0, watcher, TailConfig{ReOpen: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tail.Close(context.Background())
+
+	if err = TestFs.Rename(t.Name(), t.Name()+".1"); err != nil {
+		t.Fatal(err)
+	}
+	// Give the watcher a chance to notice the file is momentarily gone before it's recreated, as it would
+	// for a real rename-and-recreate rotation.
+	time.Sleep(3 * pollInterval)
+	newFile, err := TestFs.OpenFile(t.Name(), syscall.O_CREAT|syscall.O_APPEND|syscall.O_SYNC, 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = fmt.Fprintln(newFile, "after rotation"); err != nil {
+		t.Fatal(err)
+	}
+
+	if line := readLine(t, tail.Lines()); string(line.Text) != "after rotation" {
+		t.Errorf("expected 'after rotation', got %q", line.Text)
+	}
+}
+
+func TestTailCloseIsIdempotentAndWaitsForShutdown(t *testing.T) {
+	if _, err := TestFs.OpenFile(t.Name(), syscall.O_CREAT|syscall.O_APPEND|syscall.O_SYNC, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	watcher := &PollingFileWatcher{filename: t.Name(), fs: TestFs}
+	tail, err := newTail(TestFs, t.Name(), 0, watcher, TailConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = tail.Close(context.Background()); err != nil {
+		t.Errorf("expected a clean Close, got %v", err)
+	}
+	if _, ok := <-tail.Lines(); ok {
+		t.Errorf("expected Lines() to be closed after Close returns")
+	}
+	if err = tail.Close(context.Background()); err != nil {
+		t.Errorf("expected a second Close to also succeed, got %v", err)
+	}
+}
+
+func TestTailStopsOnTruncateWithoutReOpen(t *testing.T) {
+	file, err := TestFs.OpenFile(t.Name(), syscall.O_CREAT|syscall.O_APPEND|syscall.O_SYNC, 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = fmt.Fprintln(file, "before truncate"); err != nil {
+		t.Fatal(err)
+	}
+
+	watcher := &PollingFileWatcher{filename: t.Name(), fs: TestFs}
+	tail, err := newTail(TestFs, t.Name(), 0, watcher, TailConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tail.Close(context.Background())
+
+	if line := readLine(t, tail.Lines()); string(line.Text) != "before truncate" {
+		t.Errorf("expected 'before truncate', got %q", line.Text)
+	}
+
+	truncated, err := TestFs.OpenFile(t.Name(), syscall.O_WRONLY|syscall.O_TRUNC, 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = truncated.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if line := readLine(t, tail.Lines()); line.Err != ErrFileChanged {
+		t.Errorf("expected a final Line carrying ErrFileChanged, got %v", line.Err)
+	}
+	select {
+	case _, ok := <-tail.Lines():
+		if ok {
+			t.Errorf("expected Lines() to close after the final error Line")
+		}
+	case <-time.After(10 * pollInterval):
+		t.Fatal("Lines() didn't close after the final error Line")
+	}
+	if tail.Err() != ErrFileChanged {
+		t.Errorf("expected ErrFileChanged, got %v", tail.Err())
+	}
+}